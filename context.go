@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ctxKey is the type used for well-known context values read by the default
+// extractors. A dedicated type keeps these keys from colliding with keys set
+// by other packages.
+type ctxKey string
+
+// well-known context keys populated by applications and read by the default
+// context extractors
+const (
+	TraceIDKey   ctxKey = "trace_id"
+	SpanIDKey    ctxKey = "span_id"
+	RequestIDKey ctxKey = "request_id"
+	UserIDKey    ctxKey = "user_id"
+)
+
+// ContextExtractor pulls a single zap field out of a context.Context. The
+// bool return reports whether the field was found; when false the field is
+// not attached to the log record
+type ContextExtractor func(ctx context.Context) (zapcore.Field, bool)
+
+// internal registry of context extractors, guarded by extractorsMu
+var (
+	extractorsMu sync.RWMutex
+	extractors   = map[string]ContextExtractor{
+		FieldTraceID:   ctxValueExtractor(TraceIDKey, FieldTraceID),
+		FieldSpanID:    ctxValueExtractor(SpanIDKey, FieldSpanID),
+		FieldRequestID: ctxValueExtractor(RequestIDKey, FieldRequestID),
+		"user_id":      ctxValueExtractor(UserIDKey, "user_id"),
+	}
+)
+
+// RegisterContextExtractor registers (or replaces) the context extractor
+// stored under key. Applications use this to teach the logger about their
+// own context values, e.g. an OpenTelemetry span or a gin request ID,
+// without forking the package
+func RegisterContextExtractor(key string, fn ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors[key] = fn
+}
+
+// FieldsFromContext runs every registered extractor against ctx and returns
+// the zap fields that were found
+func FieldsFromContext(ctx context.Context) []zapcore.Field {
+	if ctx == nil {
+		return nil
+	}
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	fields := make([]zapcore.Field, 0, len(extractors))
+	for _, extract := range extractors {
+		if field, ok := extract(ctx); ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// registerConfigExtractors merges the extractors declared on a Config into
+// the global registry, so Init(cfg) can ship application-specific
+// extractors alongside the default trace/span/request/user id set
+func registerConfigExtractors(config *Config) {
+	if config == nil {
+		return
+	}
+	for key, fn := range config.ContextExtractors {
+		RegisterContextExtractor(key, fn)
+	}
+}
+
+// ctxValueExtractor builds a ContextExtractor that reads a string value from
+// ctx under key and, if present and non-empty, emits it as a zap field named
+// field
+func ctxValueExtractor(key ctxKey, field string) ContextExtractor {
+	return func(ctx context.Context) (zapcore.Field, bool) {
+		value, ok := ctx.Value(key).(string)
+		if !ok || value == "" {
+			return zapcore.Field{}, false
+		}
+		return zap.String(field, value), true
+	}
+}
+
+// WithContext returns the package-level Sugar logger carrying fields
+// extracted from ctx
+func WithContext(ctx context.Context) *Sugar {
+	return logger.WithContext(ctx)
+}
@@ -0,0 +1,34 @@
+package logger
+
+import "go.uber.org/zap"
+
+// typed field constructors re-exported from zap, so callers can build
+// structured fields without importing go.uber.org/zap themselves
+var (
+	Any        = zap.Any
+	String     = zap.String
+	Int        = zap.Int
+	Int32      = zap.Int32
+	Int64      = zap.Int64
+	Float64    = zap.Float64
+	Bool       = zap.Bool
+	Duration   = zap.Duration
+	Time       = zap.Time
+	Err        = zap.Error
+	Stack      = zap.Stack
+	Object     = zap.Object
+	Array      = zap.Array
+	Binary     = zap.Binary
+	ByteString = zap.ByteString
+)
+
+// well-known field names, kept consistent across services so structured
+// logs stay greppable across an organization
+const (
+	FieldTraceID   = "trace_id"
+	FieldSpanID    = "span_id"
+	FieldRequestID = "request_id"
+	FieldCaller    = "caller"
+	FieldStack     = "stack"
+	FieldError     = "error"
+)
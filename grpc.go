@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/grpclog"
+)
+
+// grpcVerbosity is the verbosity threshold reported by V(level) on loggers
+// created via GRPC/GRPCLogger: V(l) returns true when l <= grpcVerbosity.
+// Accessed through SetGRPCVerbosity/GetGRPCVerbosity since gRPC can call V
+// from multiple goroutines concurrently with a caller changing the threshold
+var grpcVerbosity int32
+
+// SetGRPCVerbosity changes the verbosity threshold reported by V(level) on
+// loggers created via GRPC/GRPCLogger
+func SetGRPCVerbosity(v int) {
+	atomic.StoreInt32(&grpcVerbosity, int32(v))
+}
+
+// GetGRPCVerbosity returns the current verbosity threshold set via
+// SetGRPCVerbosity (zero until then)
+func GetGRPCVerbosity() int {
+	return int(atomic.LoadInt32(&grpcVerbosity))
+}
+
+// grpcLogger adapts a sugared zap logger to grpc's grpclog.LoggerV2 interface
+type grpcLogger struct {
+	sugar *zap.SugaredLogger
+}
+
+// newGRPCLogger builds a grpcLogger on top of base, skipping two extra frames
+// of caller depth to account for this wrapper and the grpclog trampoline
+func newGRPCLogger(base *zap.Logger) *grpcLogger {
+	return &grpcLogger{sugar: base.WithOptions(zap.AddCallerSkip(2)).Sugar()}
+}
+
+// GRPC wraps sugar into a grpclog.LoggerV2, so gRPC's chatty internal
+// logging is routed through the same encoder/level/sampling policies as the
+// rest of the app. Install it globally with ReplaceGRPCLogger or
+// grpclog.SetLoggerV2
+func GRPC(sugar *Sugar) grpclog.LoggerV2 {
+	return newGRPCLogger(sugar.SugaredLogger.Desugar())
+}
+
+// GRPCLogger is the *Logger counterpart of GRPC
+func GRPCLogger(logger *Logger) grpclog.LoggerV2 {
+	return newGRPCLogger(logger.Logger)
+}
+
+// ReplaceGRPCLogger builds a Sugar from cfg and installs it as grpc's global
+// LoggerV2 via grpclog.SetLoggerV2
+func ReplaceGRPCLogger(cfg *Config) {
+	grpclog.SetLoggerV2(GRPC(NewSugaredLogger(cfg)))
+}
+
+// Info implements grpclog.LoggerV2
+func (g *grpcLogger) Info(args ...interface{}) {
+	g.sugar.Info(args...)
+}
+
+// Infoln implements grpclog.LoggerV2
+func (g *grpcLogger) Infoln(args ...interface{}) {
+	g.sugar.Info(args...)
+}
+
+// Infof implements grpclog.LoggerV2
+func (g *grpcLogger) Infof(format string, args ...interface{}) {
+	g.sugar.Infof(format, args...)
+}
+
+// Warning implements grpclog.LoggerV2
+func (g *grpcLogger) Warning(args ...interface{}) {
+	g.sugar.Warn(args...)
+}
+
+// Warningln implements grpclog.LoggerV2
+func (g *grpcLogger) Warningln(args ...interface{}) {
+	g.sugar.Warn(args...)
+}
+
+// Warningf implements grpclog.LoggerV2
+func (g *grpcLogger) Warningf(format string, args ...interface{}) {
+	g.sugar.Warnf(format, args...)
+}
+
+// Error implements grpclog.LoggerV2
+func (g *grpcLogger) Error(args ...interface{}) {
+	g.sugar.Error(args...)
+}
+
+// Errorln implements grpclog.LoggerV2
+func (g *grpcLogger) Errorln(args ...interface{}) {
+	g.sugar.Error(args...)
+}
+
+// Errorf implements grpclog.LoggerV2
+func (g *grpcLogger) Errorf(format string, args ...interface{}) {
+	g.sugar.Errorf(format, args...)
+}
+
+// Fatal implements grpclog.LoggerV2
+func (g *grpcLogger) Fatal(args ...interface{}) {
+	g.sugar.Fatal(args...)
+}
+
+// Fatalln implements grpclog.LoggerV2
+func (g *grpcLogger) Fatalln(args ...interface{}) {
+	g.sugar.Fatal(args...)
+}
+
+// Fatalf implements grpclog.LoggerV2
+func (g *grpcLogger) Fatalf(format string, args ...interface{}) {
+	g.sugar.Fatalf(format, args...)
+}
+
+// Print logs at info level, for compatibility with callers written against
+// the older grpclog.Logger interface
+func (g *grpcLogger) Print(args ...interface{}) {
+	g.sugar.Info(args...)
+}
+
+// Println logs at info level, for compatibility with callers written against
+// the older grpclog.Logger interface
+func (g *grpcLogger) Println(args ...interface{}) {
+	g.sugar.Info(args...)
+}
+
+// Printf logs at info level, for compatibility with callers written against
+// the older grpclog.Logger interface
+func (g *grpcLogger) Printf(format string, args ...interface{}) {
+	g.sugar.Infof(format, args...)
+}
+
+// V reports whether verbosity level l is enabled
+func (g *grpcLogger) V(l int) bool {
+	return int32(l) <= atomic.LoadInt32(&grpcVerbosity)
+}
@@ -1,7 +1,10 @@
 package logger
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 
 	"go.uber.org/zap"
@@ -62,6 +65,19 @@ var (
 	Errorf func(string, ...interface{})
 	Panic  func(...interface{})
 	Panicf func(string, ...interface{})
+
+	// context-aware siblings of the package functions above, see Sugar.CtxDebug and friends
+	CtxDebug  func(context.Context, ...interface{})
+	CtxDebugf func(context.Context, string, ...interface{})
+	CtxDebugw func(context.Context, string, ...interface{})
+	CtxInfo   func(context.Context, ...interface{})
+	CtxInfof  func(context.Context, string, ...interface{})
+	CtxWarn   func(context.Context, ...interface{})
+	CtxWarnf  func(context.Context, string, ...interface{})
+	CtxError  func(context.Context, ...interface{})
+	CtxErrorf func(context.Context, string, ...interface{})
+	CtxPanic  func(context.Context, ...interface{})
+	CtxPanicf func(context.Context, string, ...interface{})
 )
 
 // internal vars for inner logic
@@ -84,19 +100,37 @@ type Config struct {
 	EncoderType int
 	EncodeLevel zapcore.LevelEncoder
 	EncodeTime  zapcore.TimeEncoder
+	// ContextExtractors are merged into the global context-extractor
+	// registry on Init, in addition to the built-in trace/span/request/user
+	// id extractors. Use this to ship application-specific extractors
+	// (e.g. OpenTelemetry span context) alongside the logger config
+	ContextExtractors map[string]ContextExtractor
+	// Outputs lists the sinks the logger writes to. When empty, the logger
+	// falls back to a single os.Stdout core using EncoderType/LoggerMode,
+	// as before this field existed
+	Outputs []OutputConfig
+	// Sampling, when set, caps how many records with the same level and
+	// message are logged per Sampling.Tick, see zapcore.NewSamplerWithOptions
+	Sampling *SamplingConfig
 }
 
 // Logger structure for extending the functionality of a standard logger
 type Logger struct {
 	*zap.Logger
 	applyTemplate func(*string)
+	atomicLevel   zap.AtomicLevel
+	closers       []io.Closer
 }
 
-// Log basic method providing logging. All logging methods work through this function
-func (logger *Logger) Log(method int, template *string, fields ...zapcore.Field) {
+// Log basic method providing logging. All logging methods work through this function.
+// When ctx is non-nil, fields registered via RegisterContextExtractor are appended to fields
+func (logger *Logger) Log(ctx context.Context, method int, template *string, fields ...zapcore.Field) {
 	if logger.applyTemplate != nil {
 		logger.applyTemplate(template)
 	}
+	if ctx != nil {
+		fields = append(fields, FieldsFromContext(ctx)...)
+	}
 	log := logger.Logger
 	if template == nil {
 		log.Warn("use logger method without template", fields...)
@@ -118,22 +152,70 @@ func (logger *Logger) Log(method int, template *string, fields ...zapcore.Field)
 
 // Debug loggin debug messages
 func (logger *Logger) Debug(template string, args ...zapcore.Field) {
-	logger.Log(LogMethodDebug, &template)
+	logger.Log(nil, LogMethodDebug, &template)
 }
 
 // Error loggin errors messages
 func (logger *Logger) Error(template string, args ...zapcore.Field) {
-	logger.Log(LogMethodError, &template)
+	logger.Log(nil, LogMethodError, &template)
 }
 
 // Warn loggin errors messages
 func (logger *Logger) Warn(template string, args ...zapcore.Field) {
-	logger.Log(LogMethodWarn, &template)
+	logger.Log(nil, LogMethodWarn, &template)
 }
 
 // Panic loggin messages and run panic
 func (logger *Logger) Panic(template string, args ...zapcore.Field) {
-	logger.Log(LogMethodPanic, &template)
+	logger.Log(nil, LogMethodPanic, &template)
+}
+
+// CtxDebug loggin debug messages, attaching fields extracted from ctx
+func (logger *Logger) CtxDebug(ctx context.Context, template string, fields ...zapcore.Field) {
+	logger.Log(ctx, LogMethodDebug, &template, fields...)
+}
+
+// CtxInfo loggin info messages, attaching fields extracted from ctx
+func (logger *Logger) CtxInfo(ctx context.Context, template string, fields ...zapcore.Field) {
+	logger.Log(ctx, LogMethodInfo, &template, fields...)
+}
+
+// CtxError loggin errors messages, attaching fields extracted from ctx
+func (logger *Logger) CtxError(ctx context.Context, template string, fields ...zapcore.Field) {
+	logger.Log(ctx, LogMethodError, &template, fields...)
+}
+
+// CtxWarn loggin warning messages, attaching fields extracted from ctx
+func (logger *Logger) CtxWarn(ctx context.Context, template string, fields ...zapcore.Field) {
+	logger.Log(ctx, LogMethodWarn, &template, fields...)
+}
+
+// CtxPanic loggin messages and run panic, attaching fields extracted from ctx
+func (logger *Logger) CtxPanic(ctx context.Context, template string, fields ...zapcore.Field) {
+	logger.Log(ctx, LogMethodPanic, &template, fields...)
+}
+
+// WithContext returns a derived Logger carrying the fields extracted from ctx
+// on every subsequent record
+func (logger *Logger) WithContext(ctx context.Context) *Logger {
+	return &Logger{logger.Logger.With(FieldsFromContext(ctx)...), logger.applyTemplate, logger.atomicLevel, logger.closers}
+}
+
+// SetLevel changes the minimum level the logger emits at, effective immediately
+func (logger *Logger) SetLevel(level zapcore.Level) {
+	logger.atomicLevel.SetLevel(level)
+}
+
+// GetLevel returns the logger's current minimum level
+func (logger *Logger) GetLevel() zapcore.Level {
+	return logger.atomicLevel.Level()
+}
+
+// LevelHandler returns an http.Handler that reads (GET) or changes (PUT) the
+// logger's level, compatible with zap.AtomicLevel.ServeHTTP: GET returns
+// {"level":"info"}, PUT {"level":"debug"} changes it
+func (logger *Logger) LevelHandler() http.Handler {
+	return logger.atomicLevel
 }
 
 // Sync flushes any buffered log entries
@@ -141,15 +223,40 @@ func (logger *Logger) Sync() {
 	logger.Logger.Sync()
 }
 
+// Close flushes buffered log entries and releases any background resources
+// held by the logger's outputs (e.g. the HTTP sink's flush goroutine). Call
+// it when replacing or discarding a logger built with such outputs
+func (logger *Logger) Close() error {
+	logger.Sync()
+	var firstErr error
+	for _, closer := range logger.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
 // private constructor for create logger
-func createLogger(config *Config) *zap.Logger {
-	core := prepareConfig(config)
-	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.PanicLevel))
+func createLogger(config *Config) (*zap.Logger, zap.AtomicLevel, []io.Closer) {
+	config = normalizeConfig(config)
+	level := initialLevel(config)
+	core, closers := prepareConfig(config, level)
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.PanicLevel)), level, closers
 }
 
 // NewLogger constructor for create logger
 func NewLogger(config *Config) *zap.Logger {
-	return createLogger(config)
+	zapLogger, _, _ := createLogger(config)
+	return zapLogger
+}
+
+// NewLeveledLogger constructs a *Logger whose level can be changed at
+// runtime via SetLevel or LevelHandler. Call Close when discarding it if
+// config.Outputs includes sinks with background resources (e.g. OutputHTTP)
+func NewLeveledLogger(config *Config) *Logger {
+	zapLogger, level, closers := createLogger(config)
+	return &Logger{zapLogger, nil, level, closers}
 }
 
 // SetApplyTemplate Sets a callback that is called every time a message is generated for logging.
@@ -161,11 +268,38 @@ func (logger *Logger) SetApplyTemplate(cb func(*string)) {
 
 // Init prepare logger structure
 func Init(config *Config) {
+	registerConfigExtractors(config)
+	previous := logger
 	logger = createSugaredLogger(config)
+	bindPackageFuncs()
+	if previous != nil {
+		_ = previous.Close()
+	}
+}
+
+// SetLevel changes the minimum level the package logger emits at, effective immediately
+func SetLevel(level zapcore.Level) {
+	logger.SetLevel(level)
+}
+
+// GetLevel returns the package logger's current minimum level
+func GetLevel() zapcore.Level {
+	return logger.GetLevel()
+}
+
+// LevelHandler returns an http.Handler that reads (GET) or changes (PUT) the
+// package logger's level, see Sugar.LevelHandler
+func LevelHandler() http.Handler {
+	return logger.LevelHandler()
 }
 
 func init() {
 	logger = createSugaredLogger(nil)
+	bindPackageFuncs()
+}
+
+// bindPackageFuncs points the package-level logging vars at the current logger instance
+func bindPackageFuncs() {
 	Debug = logger.Debug
 	Debugf = logger.Debugf
 	Debugw = logger.Debugw
@@ -177,49 +311,110 @@ func init() {
 	Warnf = logger.Warnf
 	Panic = logger.Panic
 	Panicf = logger.Panicf
+
+	CtxDebug = logger.CtxDebug
+	CtxDebugf = logger.CtxDebugf
+	CtxDebugw = logger.CtxDebugw
+	CtxInfo = logger.CtxInfo
+	CtxInfof = logger.CtxInfof
+	CtxWarn = logger.CtxWarn
+	CtxWarnf = logger.CtxWarnf
+	CtxError = logger.CtxError
+	CtxErrorf = logger.CtxErrorf
+	CtxPanic = logger.CtxPanic
+	CtxPanicf = logger.CtxPanicf
 }
 
-func prepareConfig(config *Config) zapcore.Core {
-	// prepare config
+// normalizeConfig substitutes defaultConfig for a nil config and fills in any
+// empty EncodeLevel/EncodeTime, exactly once, so every caller that needs a
+// config (prepareConfig, initialLevel, ...) agrees on what "default" means
+func normalizeConfig(config *Config) *Config {
 	if config == nil {
-		config = &defaultConfig
+		return &defaultConfig
+	}
+	if config.EncodeLevel == nil {
+		config.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+	if config.EncodeTime == nil {
+		config.EncodeTime = zapcore.RFC3339TimeEncoder
+	}
+	return config
+}
+
+// prepareConfig builds the core for config, gated by level. level is a
+// zap.AtomicLevel rather than a fixed zapcore.Level so SetLevel/LevelHandler
+// can flip it at runtime without rebuilding the logger. config must already
+// be normalized via normalizeConfig
+func prepareConfig(config *Config, level zap.AtomicLevel) (zapcore.Core, []io.Closer) {
+	warnOnUnknownMode(config.LoggerMode)
+
+	var core zapcore.Core
+	var closers []io.Closer
+	if len(config.Outputs) == 0 {
+		// no outputs configured: keep the historical single os.Stdout core
+		configEncoder := encoderConfigForMode(config)
+		core = zapcore.NewCore(encoderForType(config.EncoderType, configEncoder), os.Stdout, level)
 	} else {
-		// check config params and set defaults is empty
-		if config.EncodeLevel == nil {
-			config.EncodeLevel = zapcore.CapitalLevelEncoder
-		}
-		if config.EncodeTime == nil {
-			config.EncodeTime = zapcore.RFC3339TimeEncoder
+		cores, outputClosers := buildOutputCores(config, level)
+		core = zapcore.NewTee(cores...)
+		closers = outputClosers
+	}
+
+	if config.Sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, config.Sampling.Tick, config.Sampling.First, config.Sampling.Thereafter)
+	}
+	return core, closers
+}
+
+// warnOnUnknownMode prints a hint when LoggerMode is neither "prod" nor "dev"
+func warnOnUnknownMode(loggerMode string) {
+	if loggerMode != loggerModeProd && loggerMode != loggerModeDev && len(loggerMode) > 0 {
+		fmt.Printf("wrong logger mode: %s, will use dev logger", loggerMode)
+	} else if len(loggerMode) == 0 {
+		fmt.Printf("logger mode is empty, will use dev logger")
+	}
+}
+
+// initialLevel resolves the logger's starting level: Info for prod mode,
+// Debug otherwise, overridden by the LOG_LEVEL env var when set (e.g. "debug",
+// "warn") so operators can bump verbosity without touching Config. config
+// must already be normalized via normalizeConfig
+func initialLevel(config *Config) zap.AtomicLevel {
+	level := zapcore.DebugLevel
+	if config.LoggerMode == loggerModeProd {
+		level = zapcore.InfoLevel
+	}
+	if raw := os.Getenv("LOG_LEVEL"); raw != "" {
+		var parsed zapcore.Level
+		if err := parsed.Set(raw); err != nil {
+			fmt.Printf("logger: invalid LOG_LEVEL %q, ignoring: %s\n", raw, err)
+		} else {
+			level = parsed
 		}
 	}
+	return zap.NewAtomicLevelAt(level)
+}
 
-	// prepare logger mode
+// encoderConfigForMode builds the zapcore.EncoderConfig for config.LoggerMode,
+// applying config.EncodeLevel/EncodeTime on top of zap's mode defaults
+func encoderConfigForMode(config *Config) zapcore.EncoderConfig {
 	var configEncoder zapcore.EncoderConfig
-	logLevel := zapcore.DebugLevel
-	loggerMode := config.LoggerMode
-	if loggerMode == loggerModeProd { // logger for development mode
+	if config.LoggerMode == loggerModeProd {
 		configEncoder = zap.NewProductionEncoderConfig()
-		logLevel = zapcore.InfoLevel
 	} else {
-		if loggerMode != loggerModeDev && len(loggerMode) > 0 {
-			fmt.Printf("wrong logger mode: %s, will use dev logger", loggerMode)
-		} else if len(loggerMode) == 0 {
-			fmt.Printf("logger mode is empty, will use dev logger")
-		}
 		configEncoder = zap.NewDevelopmentEncoderConfig()
 	}
-
 	configEncoder.EncodeLevel = config.EncodeLevel
 	configEncoder.EncodeTime = config.EncodeTime
+	return configEncoder
+}
 
-	// prepare encoder
-	var newEncoder zapcore.Encoder
-	switch config.EncoderType {
+// encoderForType builds a JSON or console zapcore.Encoder from configEncoder
+func encoderForType(encoderType int, configEncoder zapcore.EncoderConfig) zapcore.Encoder {
+	switch encoderType {
 	case ConsoleEncoder:
-		newEncoder = zapcore.NewConsoleEncoder(configEncoder)
+		return zapcore.NewConsoleEncoder(configEncoder)
 	default:
-		newEncoder = zapcore.NewJSONEncoder(configEncoder)
+		return zapcore.NewJSONEncoder(configEncoder)
 	}
-	core := zapcore.NewCore(newEncoder, os.Stdout, logLevel)
-	return core
 }
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestInitDefaultLevelIsInfo guards against initialLevel and prepareConfig
+// disagreeing on what a nil Config defaults to: Init(nil), and the package's
+// own init(), must start at Info (prod mode), not Debug
+func TestInitDefaultLevelIsInfo(t *testing.T) {
+	Init(nil)
+	if got := GetLevel(); got != zapcore.InfoLevel {
+		t.Fatalf("Init(nil): GetLevel() = %s, want %s", got, zapcore.InfoLevel)
+	}
+}
+
+// TestInitClosesPreviousLogger guards against Init replacing the
+// package-level logger without closing the one it replaces: repeated Init
+// calls with an OutputHTTP sink must not leak a flush goroutine per call
+func TestInitClosesPreviousLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &Config{Outputs: []OutputConfig{{Type: OutputHTTP, HTTP: &HTTPSinkConfig{URL: server.URL}}}}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 5; i++ {
+		Init(cfg)
+	}
+	defer Init(nil)
+
+	// give any leaked goroutines a moment to show up in the count
+	time.Sleep(50 * time.Millisecond)
+	if after := runtime.NumGoroutine(); after > before+1 {
+		t.Fatalf("Init leaked goroutines: before=%d after=%d", before, after)
+	}
+}
+
+// TestLoggerCtxInfo guards against *Logger missing a Ctx-aware sibling that
+// *Sugar already has: it must compile and log with a ctx like CtxDebug/
+// CtxWarn/CtxError/CtxPanic already do
+func TestLoggerCtxInfo(t *testing.T) {
+	logger := NewLeveledLogger(nil)
+	ctx := context.WithValue(context.Background(), RequestIDKey, "req-1")
+	logger.CtxInfo(ctx, "hello")
+}
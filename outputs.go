@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// output destinations supported by OutputConfig.Type
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputFile   = "file"
+	OutputSyslog = "syslog"
+	// OutputHTTP is the reference batching HTTP sink, see HTTPSinkConfig
+	OutputHTTP = "http"
+)
+
+// OutputConfig describes a single sink in the multi-sink core built by
+// prepareConfig. MinLevel/MaxLevel default to Debug/Fatal when left nil, so a
+// typical setup only sets the bound it actually needs, e.g. MinLevel for an
+// errors-only file next to an unbounded stdout console
+type OutputConfig struct {
+	Type        string
+	MinLevel    *zapcore.Level
+	MaxLevel    *zapcore.Level
+	EncoderType int
+	File        *FileConfig
+	HTTP        *HTTPSinkConfig
+	// Custom carries settings for output types registered via RegisterSink
+	Custom map[string]any
+}
+
+// FileConfig configures rotation for an OutputConfig of Type OutputFile,
+// backed by lumberjack
+type FileConfig struct {
+	Path       string
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+	Compress   bool
+}
+
+// SamplingConfig maps onto zapcore.NewSamplerWithOptions, capping throughput
+// per Tick to First entries logged as-is plus one in every Thereafter after that
+type SamplingConfig struct {
+	Tick       time.Duration
+	First      int
+	Thereafter int
+}
+
+// buildOutputCores turns config.Outputs into one zapcore.Core per entry,
+// skipping (and reporting) any output that fails to build rather than
+// aborting logger construction. Every core also honors level, the logger's
+// dynamic AtomicLevel, in addition to its own MinLevel/MaxLevel range.
+// closers collects any resources (e.g. the HTTP sink's flush goroutine) that
+// must be released via Close when the logger using these cores is discarded
+func buildOutputCores(config *Config, level zap.AtomicLevel) (cores []zapcore.Core, closers []io.Closer) {
+	cores = make([]zapcore.Core, 0, len(config.Outputs))
+	for _, output := range config.Outputs {
+		core, closer, err := buildOutputCore(config, output, level)
+		if err != nil {
+			fmt.Printf("logger: skipping output %q: %s\n", output.Type, err)
+			continue
+		}
+		cores = append(cores, core)
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+	return cores, closers
+}
+
+func buildOutputCore(config *Config, output OutputConfig, level zap.AtomicLevel) (zapcore.Core, io.Closer, error) {
+	levelEnabler := outputLevelEnabler(output, level)
+
+	if output.Type == OutputHTTP {
+		if output.HTTP == nil {
+			return nil, nil, fmt.Errorf("output type %q requires HTTP config", output.Type)
+		}
+		encoder := encoderForType(output.EncoderType, encoderConfigForMode(config))
+		sink := NewHTTPSink(*output.HTTP, encoder, levelEnabler)
+		return sink, sink, nil
+	}
+
+	if output.Type == OutputSyslog {
+		encoder := encoderForType(output.EncoderType, encoderConfigForMode(config))
+		core, err := newSyslogCore(encoder, levelEnabler)
+		if err != nil {
+			return nil, nil, err
+		}
+		return core, nil, nil
+	}
+
+	sink, customEncoder, err := outputWriteSyncer(output)
+	if err != nil {
+		return nil, nil, err
+	}
+	encoder := customEncoder
+	if encoder == nil {
+		encoder = encoderForType(output.EncoderType, encoderConfigForMode(config))
+	}
+
+	return zapcore.NewCore(encoder, sink, levelEnabler), nil, nil
+}
+
+// outputLevelEnabler builds the LevelEnabler for output, intersecting the
+// logger's dynamic level with the output's own MinLevel/MaxLevel range
+func outputLevelEnabler(output OutputConfig, level zap.AtomicLevel) zapcore.LevelEnabler {
+	minLevel := zapcore.DebugLevel
+	if output.MinLevel != nil {
+		minLevel = *output.MinLevel
+	}
+	maxLevel := zapcore.FatalLevel
+	if output.MaxLevel != nil {
+		maxLevel = *output.MaxLevel
+	}
+	return zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return level.Enabled(l) && l >= minLevel && l <= maxLevel
+	})
+}
+
+// outputWriteSyncer resolves the zapcore.WriteSyncer for a single output, and
+// an optional encoder override for sinks (registered via RegisterSink) that
+// need a specific wire format
+func outputWriteSyncer(output OutputConfig) (zapcore.WriteSyncer, zapcore.Encoder, error) {
+	switch output.Type {
+	case OutputStdout, "":
+		return zapcore.AddSync(os.Stdout), nil, nil
+	case OutputStderr:
+		return zapcore.AddSync(os.Stderr), nil, nil
+	case OutputFile:
+		if output.File == nil {
+			return nil, nil, fmt.Errorf("output type %q requires File config", output.Type)
+		}
+		return zapcore.AddSync(&lumberjack.Logger{
+			Filename:   output.File.Path,
+			MaxSize:    output.File.MaxSizeMB,
+			MaxAge:     output.File.MaxAgeDays,
+			MaxBackups: output.File.MaxBackups,
+			Compress:   output.File.Compress,
+		}), nil, nil
+	default:
+		factory, ok := lookupSink(output.Type)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown output type: %q", output.Type)
+		}
+		return factory(output.Custom)
+	}
+}
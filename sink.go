@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SinkFactory builds the WriteSyncer/Encoder pair for a custom output type
+// registered via RegisterSink. cfg carries the output's declarative settings,
+// taken verbatim from OutputConfig.Custom
+type SinkFactory func(cfg map[string]any) (zapcore.WriteSyncer, zapcore.Encoder, error)
+
+var (
+	sinksMu sync.RWMutex
+	sinks   = map[string]SinkFactory{}
+)
+
+// RegisterSink registers a custom output destination under name, so it can
+// be referenced from Config.Outputs as OutputConfig{Type: name, Custom: cfg}
+// without forking the package. Loki/Dingtalk-style integrations are typical
+// uses; see NewHTTPSink for a ready-made batching HTTP sink
+func RegisterSink(name string, factory SinkFactory) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks[name] = factory
+}
+
+// lookupSink returns the sink factory registered under name, if any
+func lookupSink(name string) (SinkFactory, bool) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	factory, ok := sinks[name]
+	return factory, ok
+}
+
+// HTTPSinkConfig configures the reference batching HTTP sink built by
+// NewHTTPSink
+type HTTPSinkConfig struct {
+	// URL receives the batched newline-delimited JSON payload via POST
+	URL string
+	// FlushInterval bounds how long entries sit in the buffer before being
+	// sent, regardless of BatchSize. Defaults to 5s
+	FlushInterval time.Duration
+	// BatchSize flushes early once this many entries are buffered. Defaults to 100
+	BatchSize int
+	// MaxRetries is how many times a failed flush is retried, with a linear
+	// backoff between attempts, before the batch is degraded to stderr. Defaults to 3
+	MaxRetries int
+	// Client is the http.Client used to POST batches. Defaults to a client with a 10s timeout
+	Client *http.Client
+}
+
+// httpSinkState is the state shared by every zapcore.Core derived from the
+// same NewHTTPSink call via With()
+type httpSinkState struct {
+	cfg HTTPSinkConfig
+
+	mu     sync.Mutex
+	buffer [][]byte
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// httpSink is a zapcore.Core that buffers encoded entries and periodically
+// flushes them as newline-delimited JSON to cfg.URL. It implements
+// zapcore.Core directly (rather than only zapcore.WriteSyncer) so every
+// entry is encoded as structured JSON exactly once, with fields surviving as
+// JSON keys, and so flushing can be driven by size/time thresholds instead of
+// a single Write call. It also implements io.Closer: callers that discard a
+// logger built with this sink must call Close to stop its flush goroutine
+type httpSink struct {
+	encoder      zapcore.Encoder
+	levelEnabler zapcore.LevelEnabler
+	shared       *httpSinkState
+}
+
+// NewHTTPSink builds a zapcore.Core that batches entries encoded with
+// encoder and POSTs them as newline-delimited JSON to cfg.URL. It degrades
+// gracefully to stderr when the endpoint can't be reached after retrying.
+// The returned *httpSink also implements io.Closer; call Close when the
+// logger using it is replaced or discarded, or its flush goroutine leaks
+func NewHTTPSink(cfg HTTPSinkConfig, encoder zapcore.Encoder, levelEnabler zapcore.LevelEnabler) *httpSink {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	shared := &httpSinkState{cfg: cfg, stop: make(chan struct{})}
+	go shared.flushLoop()
+
+	return &httpSink{encoder: encoder, levelEnabler: levelEnabler, shared: shared}
+}
+
+// Enabled implements zapcore.Core
+func (s *httpSink) Enabled(level zapcore.Level) bool {
+	return s.levelEnabler.Enabled(level)
+}
+
+// With implements zapcore.Core
+func (s *httpSink) With(fields []zapcore.Field) zapcore.Core {
+	encoder := s.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return &httpSink{encoder: encoder, levelEnabler: s.levelEnabler, shared: s.shared}
+}
+
+// Check implements zapcore.Core
+func (s *httpSink) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if s.Enabled(entry.Level) {
+		return checked.AddCore(entry, s)
+	}
+	return checked
+}
+
+// Write implements zapcore.Core
+func (s *httpSink) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := s.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	line := append([]byte(nil), buf.Bytes()...)
+	buf.Free()
+	s.shared.enqueue(line)
+	return nil
+}
+
+// Sync implements zapcore.Core
+func (s *httpSink) Sync() error {
+	return s.shared.flush()
+}
+
+// Close implements io.Closer: it stops the background flush goroutine and
+// flushes any entries still buffered. Safe to call more than once, and from
+// any core derived from the same NewHTTPSink via With()
+func (s *httpSink) Close() error {
+	s.shared.close()
+	return s.shared.flush()
+}
+
+func (s *httpSinkState) enqueue(line []byte) {
+	s.mu.Lock()
+	s.buffer = append(s.buffer, line)
+	full := len(s.buffer) >= s.cfg.BatchSize
+	s.mu.Unlock()
+	if full {
+		_ = s.flush()
+	}
+}
+
+func (s *httpSinkState) flushLoop() {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = s.flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// close stops flushLoop; safe to call more than once
+func (s *httpSinkState) close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// flush POSTs the buffered batch, retrying with a linear backoff, and falls
+// back to writing the batch to stderr if every attempt fails
+func (s *httpSinkState) flush() error {
+	s.mu.Lock()
+	batch := s.buffer
+	s.buffer = nil
+	s.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	for _, line := range batch {
+		body.Write(line)
+	}
+
+	var err error
+	for attempt := 0; attempt <= s.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+		}
+		if err = s.post(body.Bytes()); err == nil {
+			return nil
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "logger: http sink %q unreachable after %d attempts, writing batch to stderr: %s\n", s.cfg.URL, s.cfg.MaxRetries+1, err)
+	os.Stderr.Write(body.Bytes())
+	return err
+}
+
+func (s *httpSinkState) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("http sink: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
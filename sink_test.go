@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestHTTPSinkFlushesToServer verifies a buffered entry reaches the
+// configured endpoint once the batch size is hit
+func TestHTTPSinkFlushesToServer(t *testing.T) {
+	var received int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(HTTPSinkConfig{URL: server.URL, BatchSize: 1}, zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zap.NewAtomicLevelAt(zapcore.DebugLevel))
+	defer sink.Close()
+
+	if err := sink.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "hello"}, nil); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&received) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&received) == 0 {
+		t.Fatal("server never received the flushed batch")
+	}
+}
+
+// TestHTTPSinkDegradesToStderr verifies a batch that can't be delivered,
+// after exhausting retries, is reported rather than lost silently
+func TestHTTPSinkDegradesToStderr(t *testing.T) {
+	sink := NewHTTPSink(HTTPSinkConfig{URL: "http://127.0.0.1:0", MaxRetries: 1}, zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zap.NewAtomicLevelAt(zapcore.DebugLevel))
+	defer sink.Close()
+
+	if err := sink.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "unreachable"}, nil); err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	if err := sink.Sync(); err == nil {
+		t.Fatal("expected Sync to report the delivery failure after retries are exhausted")
+	}
+}
+
+// TestHTTPSinkCloseStopsFlushLoop verifies Close is safe to call more than
+// once and stops the background flush goroutine
+func TestHTTPSinkCloseStopsFlushLoop(t *testing.T) {
+	sink := NewHTTPSink(HTTPSinkConfig{URL: "http://127.0.0.1:0"}, zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zap.NewAtomicLevelAt(zapcore.DebugLevel))
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("second Close: %s", err)
+	}
+	select {
+	case <-sink.shared.stop:
+	default:
+		t.Fatal("Close did not signal the flush goroutine to stop")
+	}
+}
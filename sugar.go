@@ -1,6 +1,10 @@
 package logger
 
 import (
+	"context"
+	"io"
+	"net/http"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -9,14 +13,24 @@ import (
 type Sugar struct {
 	*zap.SugaredLogger
 	applyTemplate func(*string)
+	atomicLevel   zap.AtomicLevel
+	closers       []io.Closer
 }
 
-// Log basic method providing logging. All logging methods work through this function
-func (logger *Sugar) Log(method int, template *string, args ...interface{}) {
+// Log basic method providing logging. All logging methods work through this function.
+// When ctx is non-nil, fields registered via RegisterContextExtractor are attached to the record
+func (logger *Sugar) Log(ctx context.Context, method int, template *string, args ...interface{}) {
 	if logger.applyTemplate != nil {
 		logger.applyTemplate(template)
 	}
 	log := logger.SugaredLogger
+	if ctxFields := FieldsFromContext(ctx); len(ctxFields) > 0 {
+		ctxArgs := make([]interface{}, len(ctxFields))
+		for i, field := range ctxFields {
+			ctxArgs[i] = field
+		}
+		log = log.With(ctxArgs...)
+	}
 	if method > LogMethodPanic && template == nil {
 		log.Warnf("use logger method without template", args...)
 		template = &emptyTemplate
@@ -49,52 +63,151 @@ func (logger *Sugar) Log(method int, template *string, args ...interface{}) {
 
 // Debug loggin debug messages
 func (logger *Sugar) Debug(args ...interface{}) {
-	logger.Log(LogMethodDebug, nil, args...)
+	logger.Log(nil, LogMethodDebug, nil, args...)
 }
 
 // Debugf loggin debug messages whit special template
 // Debugf uses fmt.Sprintf to log a templated message
 func (logger *Sugar) Debugf(template string, args ...interface{}) {
-	logger.Log(LogMethodDebugf, &template, args...)
+	logger.Log(nil, LogMethodDebugf, &template, args...)
 }
 
 // Debugw loggin debug messages
 // Debugw logs a message with some additional context. The variadic key-value pairs are treated as they are in With
 func (logger *Sugar) Debugw(template string, keyAndValues ...interface{}) {
-	logger.Log(LogMethodDebugw, &template, keyAndValues...)
+	logger.Log(nil, LogMethodDebugw, &template, keyAndValues...)
 }
 
 // Warn loggin errors messages
 func (logger *Sugar) Warn(args ...interface{}) {
-	logger.Log(LogMethodWarn, nil, args...)
+	logger.Log(nil, LogMethodWarn, nil, args...)
 }
 
 // Warnf loggin errors messages
 // Warnf uses fmt.Sprintf to log a templated message.
 func (logger *Sugar) Warnf(template string, args ...interface{}) {
-	logger.Log(LogMethodWarnf, &template, args...)
+	logger.Log(nil, LogMethodWarnf, &template, args...)
 }
 
 // Error loggin errors messages
 func (logger *Sugar) Error(args ...interface{}) {
-	logger.Log(LogMethodError, nil, args...)
+	logger.Log(nil, LogMethodError, nil, args...)
 }
 
 // Errorf loggin errors messages
 // Errorf uses fmt.Sprintf to log a templated message
 func (logger *Sugar) Errorf(template string, args ...interface{}) {
-	logger.Log(LogMethodErrorf, &template, args...)
+	logger.Log(nil, LogMethodErrorf, &template, args...)
 }
 
 // Panic loggin messages and run panic
 func (logger *Sugar) Panic(args ...interface{}) {
-	logger.Log(LogMethodPanic, nil, args...)
+	logger.Log(nil, LogMethodPanic, nil, args...)
 }
 
 // Panicf loggin messages and run panic
 // Panicf uses fmt.Sprintf to log a templated message
 func (logger *Sugar) Panicf(template string, args ...interface{}) {
-	logger.Log(LogMethodPanicf, &template, args...)
+	logger.Log(nil, LogMethodPanicf, &template, args...)
+}
+
+// CtxDebug loggin debug messages, attaching fields extracted from ctx
+func (logger *Sugar) CtxDebug(ctx context.Context, args ...interface{}) {
+	logger.Log(ctx, LogMethodDebug, nil, args...)
+}
+
+// CtxDebugf loggin debug messages whit special template, attaching fields extracted from ctx
+func (logger *Sugar) CtxDebugf(ctx context.Context, template string, args ...interface{}) {
+	logger.Log(ctx, LogMethodDebugf, &template, args...)
+}
+
+// CtxDebugw loggin debug messages, attaching fields extracted from ctx.
+// The variadic key-value pairs are treated as they are in With
+func (logger *Sugar) CtxDebugw(ctx context.Context, template string, keyAndValues ...interface{}) {
+	logger.Log(ctx, LogMethodDebugw, &template, keyAndValues...)
+}
+
+// CtxInfo loggin info messages, attaching fields extracted from ctx
+func (logger *Sugar) CtxInfo(ctx context.Context, args ...interface{}) {
+	logger.Log(ctx, LogMethodInfo, nil, args...)
+}
+
+// CtxInfof loggin info messages whit special template, attaching fields extracted from ctx
+func (logger *Sugar) CtxInfof(ctx context.Context, template string, args ...interface{}) {
+	logger.Log(ctx, LogMethodInfof, &template, args...)
+}
+
+// CtxWarn loggin warning messages, attaching fields extracted from ctx
+func (logger *Sugar) CtxWarn(ctx context.Context, args ...interface{}) {
+	logger.Log(ctx, LogMethodWarn, nil, args...)
+}
+
+// CtxWarnf loggin warning messages, attaching fields extracted from ctx
+func (logger *Sugar) CtxWarnf(ctx context.Context, template string, args ...interface{}) {
+	logger.Log(ctx, LogMethodWarnf, &template, args...)
+}
+
+// CtxError loggin errors messages, attaching fields extracted from ctx
+func (logger *Sugar) CtxError(ctx context.Context, args ...interface{}) {
+	logger.Log(ctx, LogMethodError, nil, args...)
+}
+
+// CtxErrorf loggin errors messages, attaching fields extracted from ctx
+func (logger *Sugar) CtxErrorf(ctx context.Context, template string, args ...interface{}) {
+	logger.Log(ctx, LogMethodErrorf, &template, args...)
+}
+
+// CtxPanic loggin messages and run panic, attaching fields extracted from ctx
+func (logger *Sugar) CtxPanic(ctx context.Context, args ...interface{}) {
+	logger.Log(ctx, LogMethodPanic, nil, args...)
+}
+
+// CtxPanicf loggin messages and run panic, attaching fields extracted from ctx
+func (logger *Sugar) CtxPanicf(ctx context.Context, template string, args ...interface{}) {
+	logger.Log(ctx, LogMethodPanicf, &template, args...)
+}
+
+// WithContext returns a derived Sugar carrying the fields extracted from ctx
+// on every subsequent record
+func (logger *Sugar) WithContext(ctx context.Context) *Sugar {
+	ctxFields := FieldsFromContext(ctx)
+	ctxArgs := make([]interface{}, len(ctxFields))
+	for i, field := range ctxFields {
+		ctxArgs[i] = field
+	}
+	return &Sugar{logger.SugaredLogger.With(ctxArgs...), logger.applyTemplate, logger.atomicLevel, logger.closers}
+}
+
+// With returns a derived Sugar carrying fields on every subsequent record
+func (logger *Sugar) With(fields ...zap.Field) *Sugar {
+	args := make([]interface{}, len(fields))
+	for i, field := range fields {
+		args[i] = field
+	}
+	return &Sugar{logger.SugaredLogger.With(args...), logger.applyTemplate, logger.atomicLevel, logger.closers}
+}
+
+// Named adds a sub-scope to the logger's name. Names are joined with dots,
+// e.g. logger.Named("worker").Named("poller") logs under "worker.poller"
+func (logger *Sugar) Named(name string) *Sugar {
+	return &Sugar{logger.SugaredLogger.Named(name), logger.applyTemplate, logger.atomicLevel, logger.closers}
+}
+
+// SetLevel changes the minimum level the logger emits at, effective immediately
+func (logger *Sugar) SetLevel(level zapcore.Level) {
+	logger.atomicLevel.SetLevel(level)
+}
+
+// GetLevel returns the logger's current minimum level
+func (logger *Sugar) GetLevel() zapcore.Level {
+	return logger.atomicLevel.Level()
+}
+
+// LevelHandler returns an http.Handler that reads (GET) or changes (PUT) the
+// logger's level, compatible with zap.AtomicLevel.ServeHTTP: GET returns
+// {"level":"info"}, PUT {"level":"debug"} changes it
+func (logger *Sugar) LevelHandler() http.Handler {
+	return logger.atomicLevel
 }
 
 // Sync flushes any buffered log entries
@@ -102,10 +215,26 @@ func (logger *Sugar) Sync() {
 	logger.SugaredLogger.Sync()
 }
 
+// Close flushes buffered log entries and releases any background resources
+// held by the logger's outputs (e.g. the HTTP sink's flush goroutine). Call
+// it when replacing or discarding a logger built with such outputs
+func (logger *Sugar) Close() error {
+	logger.Sync()
+	var firstErr error
+	for _, closer := range logger.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
 // private constructor for create sugared logger
 func createSugaredLogger(config *Config) *Sugar {
-	core := prepareConfig(config)
-	return &Sugar{zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.PanicLevel)).Sugar(), nil}
+	config = normalizeConfig(config)
+	level := initialLevel(config)
+	core, closers := prepareConfig(config, level)
+	return &Sugar{zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.PanicLevel)).Sugar(), nil, level, closers}
 }
 
 // NewSugaredLogger constructor for create sugared logger
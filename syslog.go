@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"log/syslog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogCore is a zapcore.Core that writes each entry to syslog at the
+// priority matching its zap level, instead of a single fixed priority for
+// every record, so Error/Warn/Debug entries keep their severity for
+// downstream routing/filtering in syslog or journald
+type syslogCore struct {
+	encoder      zapcore.Encoder
+	levelEnabler zapcore.LevelEnabler
+	writer       *syslog.Writer
+}
+
+// newSyslogCore opens a syslog connection and wraps it in a Core that encodes
+// entries with encoder, gated by levelEnabler
+func newSyslogCore(encoder zapcore.Encoder, levelEnabler zapcore.LevelEnabler) (*syslogCore, error) {
+	writer, err := syslog.New(syslog.LOG_INFO, "")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogCore{encoder: encoder, levelEnabler: levelEnabler, writer: writer}, nil
+}
+
+// Enabled implements zapcore.Core
+func (c *syslogCore) Enabled(level zapcore.Level) bool {
+	return c.levelEnabler.Enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return &syslogCore{encoder: encoder, levelEnabler: c.levelEnabler, writer: c.writer}
+}
+
+// Check implements zapcore.Core
+func (c *syslogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write implements zapcore.Core, routing entry to the syslog.Writer method
+// matching its level so the record keeps its severity in syslog/journald
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	msg := buf.String()
+	buf.Free()
+
+	switch entry.Level {
+	case zapcore.DebugLevel:
+		return c.writer.Debug(msg)
+	case zapcore.InfoLevel:
+		return c.writer.Info(msg)
+	case zapcore.WarnLevel:
+		return c.writer.Warning(msg)
+	case zapcore.ErrorLevel:
+		return c.writer.Err(msg)
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return c.writer.Crit(msg)
+	default: // zapcore.FatalLevel and anything else unexpectedly severe
+		return c.writer.Emerg(msg)
+	}
+}
+
+// Sync implements zapcore.Core. Each Write already delivers synchronously to
+// the syslog daemon, so there is nothing to flush
+func (c *syslogCore) Sync() error {
+	return nil
+}